@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIVerifier checks whether an address at domain is deliverable using a
+// provider-specific API instead of a raw SMTP RCPT probe. Large providers
+// grey-list or tarpit probe connections, which makes SMTP-only checks
+// produce false negatives for Gmail, Yahoo, and Outlook/Hotmail addresses.
+type APIVerifier interface {
+	Check(username, domain string) (EmailResult, error)
+}
+
+var (
+	apiVerifierMu sync.RWMutex
+	apiVerifiers  = map[string]APIVerifier{}
+)
+
+// RegisterAPIVerifier associates an APIVerifier with an MX hostname suffix
+// (e.g. "google.com"). lookupAPIVerifier matches the longest registered
+// suffix of a domain's MX host against this registry.
+func RegisterAPIVerifier(hostSuffix string, v APIVerifier) {
+	apiVerifierMu.Lock()
+	defer apiVerifierMu.Unlock()
+	apiVerifiers[strings.ToLower(hostSuffix)] = v
+}
+
+func init() {
+	if envEnabled("ENABLE_GMAIL_API_VERIFIER", true) {
+		RegisterAPIVerifier("google.com", gmailVerifier{})
+	}
+	if envEnabled("ENABLE_YAHOO_API_VERIFIER", true) {
+		RegisterAPIVerifier("yahoodns.net", yahooVerifier{})
+	}
+	if envEnabled("ENABLE_HOTMAIL_API_VERIFIER", true) {
+		RegisterAPIVerifier("outlook.com", hotmailVerifier{})
+	}
+}
+
+// envEnabled reads a boolean-ish env var, defaulting to def when unset.
+func envEnabled(name string, def bool) bool {
+	v := strings.ToLower(os.Getenv(name))
+	switch v {
+	case "":
+		return def
+	case "0", "false", "off", "no":
+		return false
+	default:
+		return true
+	}
+}
+
+// lookupAPIVerifier returns the verifier registered for the longest
+// matching suffix of mxHost, if any.
+func lookupAPIVerifier(mxHost string) (APIVerifier, bool) {
+	mxHost = strings.ToLower(strings.TrimSuffix(mxHost, "."))
+
+	apiVerifierMu.RLock()
+	defer apiVerifierMu.RUnlock()
+
+	var (
+		best    APIVerifier
+		bestLen int
+		matched bool
+	)
+	for suffix, v := range apiVerifiers {
+		if strings.HasSuffix(mxHost, suffix) && len(suffix) > bestLen {
+			best = v
+			bestLen = len(suffix)
+			matched = true
+		}
+	}
+	return best, matched
+}
+
+var apiVerifierHTTPClient = &http.Client{Timeout: 5 * time.Second}
+
+// gmailVerifier checks Gmail/Google Workspace addresses via Gmail's account
+// recovery endpoint, which sets a session cookie only when the address
+// belongs to a real account.
+type gmailVerifier struct{}
+
+func (gmailVerifier) Check(username, domain string) (EmailResult, error) {
+	address := username + "@" + domain
+
+	query := url.Values{"email": {address}}.Encode()
+	req, err := http.NewRequest(http.MethodGet, "https://mail.google.com/mail/gxlu?"+query, nil)
+	if err != nil {
+		return EmailResult{}, err
+	}
+	resp, err := apiVerifierHTTPClient.Do(req)
+	if err != nil {
+		return EmailResult{}, err
+	}
+	defer resp.Body.Close()
+
+	deliverable := false
+	for _, c := range resp.Cookies() {
+		if c.Name == "COMPASS" {
+			deliverable = true
+			break
+		}
+	}
+
+	return EmailResult{
+		Address:     address,
+		Username:    username,
+		Domain:      domain,
+		HostExists:  true,
+		Deliverable: deliverable,
+	}, nil
+}
+
+// yahooVerifier checks Yahoo addresses via the account-creation field
+// validator, which reports a username as taken when an account already
+// exists for it.
+type yahooVerifier struct{}
+
+func (yahooVerifier) Check(username, domain string) (EmailResult, error) {
+	address := username + "@" + domain
+
+	query := url.Values{"validateField": {"yid"}, "yid": {username}}.Encode()
+	resp, err := apiVerifierHTTPClient.Get("https://login.yahoo.com/account/module/create?" + query)
+	if err != nil {
+		return EmailResult{}, err
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Errors []struct {
+			Name string `json:"name"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return EmailResult{}, err
+	}
+
+	taken := false
+	for _, e := range payload.Errors {
+		if e.Name == "IDENTIFIER_EXISTS" {
+			taken = true
+			break
+		}
+	}
+
+	return EmailResult{
+		Address:     address,
+		Username:    username,
+		Domain:      domain,
+		HostExists:  true,
+		Deliverable: taken,
+	}, nil
+}
+
+// hotmailVerifier checks Outlook/Hotmail/MSN addresses via Microsoft's
+// GetCredentialType endpoint, which reports whether an account exists for a
+// given address before any password is sent.
+type hotmailVerifier struct{}
+
+func (hotmailVerifier) Check(username, domain string) (EmailResult, error) {
+	address := username + "@" + domain
+
+	body, err := json.Marshal(map[string]string{"Username": address})
+	if err != nil {
+		return EmailResult{}, err
+	}
+	req, err := http.NewRequest(http.MethodPost, "https://login.live.com/GetCredentialType.srf", strings.NewReader(string(body)))
+	if err != nil {
+		return EmailResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := apiVerifierHTTPClient.Do(req)
+	if err != nil {
+		return EmailResult{}, err
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		IfExistsResult int `json:"IfExistsResult"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return EmailResult{}, err
+	}
+
+	return EmailResult{
+		Address:     address,
+		Username:    username,
+		Domain:      domain,
+		HostExists:  true,
+		Deliverable: payload.IfExistsResult == 0,
+	}, nil
+}
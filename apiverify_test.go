@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEnvEnabled(t *testing.T) {
+	const key = "TEST_ENV_ENABLED"
+	defer os.Unsetenv(key)
+
+	os.Unsetenv(key)
+	if !envEnabled(key, true) {
+		t.Error("unset env: expected default true to be returned")
+	}
+	if envEnabled(key, false) {
+		t.Error("unset env: expected default false to be returned")
+	}
+
+	for _, v := range []string{"0", "false", "off", "no", "FALSE", "Off"} {
+		os.Setenv(key, v)
+		if envEnabled(key, true) {
+			t.Errorf("env=%q: expected envEnabled to be false", v)
+		}
+	}
+
+	for _, v := range []string{"1", "true", "on", "yes"} {
+		os.Setenv(key, v)
+		if !envEnabled(key, false) {
+			t.Errorf("env=%q: expected envEnabled to be true", v)
+		}
+	}
+
+	// An unrecognized value (e.g. a typo'd "flase") resolves to enabled, not
+	// disabled — only the known falsy strings above turn the check off.
+	os.Setenv(key, "flase")
+	if !envEnabled(key, false) {
+		t.Error(`env="flase": expected unrecognized value to resolve to enabled, not disabled`)
+	}
+}
+
+func TestLookupAPIVerifierLongestSuffixMatch(t *testing.T) {
+	apiVerifierMu.Lock()
+	origVerifiers := apiVerifiers
+	apiVerifiers = map[string]APIVerifier{}
+	apiVerifierMu.Unlock()
+	defer func() {
+		apiVerifierMu.Lock()
+		apiVerifiers = origVerifiers
+		apiVerifierMu.Unlock()
+	}()
+
+	outer := gmailVerifier{}
+	inner := hotmailVerifier{}
+	RegisterAPIVerifier("mail.example.com", outer)
+	RegisterAPIVerifier("aspmx.l.mail.example.com", inner)
+
+	v, ok := lookupAPIVerifier("aspmx.l.mail.example.com")
+	if !ok {
+		t.Fatal("expected a verifier to match")
+	}
+	if v != APIVerifier(inner) {
+		t.Error("expected the longer, more specific suffix to win over the shorter one it also matches")
+	}
+
+	v, ok = lookupAPIVerifier("other.mail.example.com")
+	if !ok {
+		t.Fatal("expected a verifier to match via the shorter suffix")
+	}
+	if v != APIVerifier(outer) {
+		t.Error("expected the shorter suffix to match when the longer one doesn't apply")
+	}
+
+	if _, ok := lookupAPIVerifier("unrelated.com"); ok {
+		t.Error("expected no match for a host with no registered suffix")
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxConcurrency returns the worker pool size for batch verification,
+// configurable via MAX_CONCURRENCY since SMTP verifications are I/O bound
+// and running them serially would be dramatically slower.
+func maxConcurrency() int {
+	if v := os.Getenv("MAX_CONCURRENCY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 10
+}
+
+// batchRequestTimeout bounds how long a single address's validation may
+// take within a batch, so one tarpitting MX doesn't stall the whole batch.
+func batchRequestTimeout() time.Duration {
+	return envDuration("BATCH_REQUEST_TIMEOUT", 20*time.Second)
+}
+
+// smtpSessionTimeout bounds an entire SMTP dialogue (HELO/MAIL/RCPT), via
+// conn.SetDeadline in checkSMTPDeliverable. Without it, a server that
+// tarpits past the connect step — accepting the TCP connection but never
+// replying — would hang its goroutine forever while still holding
+// mxSessionLock(mxHost), wedging every other request to that MX host.
+func smtpSessionTimeout() time.Duration {
+	return envDuration("SMTP_SESSION_TIMEOUT", 20*time.Second)
+}
+
+// validateEmailWithTimeout runs validateEmail but gives up and returns a
+// zero-value result (beyond the address itself) if it takes longer than
+// batchRequestTimeout.
+func validateEmailWithTimeout(email string) EmailResult {
+	resultCh := make(chan EmailResult, 1)
+	go func() {
+		resultCh <- validateEmail(email)
+	}()
+
+	select {
+	case result := <-resultCh:
+		return result
+	case <-time.After(batchRequestTimeout()):
+		return EmailResult{Address: email}
+	}
+}
+
+var (
+	mxSessionLocksMu sync.Mutex
+	mxSessionLocks   = map[string]*sync.Mutex{}
+)
+
+// mxSessionLock returns the mutex guarding SMTP sessions against mxHost, so
+// a batch run never opens more than one connection to the same remote MX
+// at a time and risks getting blocklisted.
+func mxSessionLock(mxHost string) *sync.Mutex {
+	mxHost = strings.ToLower(mxHost)
+
+	mxSessionLocksMu.Lock()
+	defer mxSessionLocksMu.Unlock()
+
+	if lock, ok := mxSessionLocks[mxHost]; ok {
+		return lock
+	}
+	lock := &sync.Mutex{}
+	mxSessionLocks[mxHost] = lock
+	return lock
+}
+
+func init() {
+	go sweepStaleStateLoop(cacheSweepInterval())
+}
+
+// sweepStaleStateLoop periodically evicts expired mxCache/smtpCache entries
+// and idle per-MX session locks, so the batch endpoint's unbounded,
+// unauthenticated address list can't grow all three without bound.
+func sweepStaleStateLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		sweepExpiredCaches()
+		sweepIdleMXSessionLocks()
+	}
+}
+
+// sweepIdleMXSessionLocks drops locks that aren't currently held. Every
+// caller reaches a lock through mxSessionLock, which takes
+// mxSessionLocksMu, so TryLock succeeding here (under the same mutex) means
+// no session is using mxHost right now; a later request for the same host
+// just allocates a fresh lock.
+func sweepIdleMXSessionLocks() {
+	mxSessionLocksMu.Lock()
+	defer mxSessionLocksMu.Unlock()
+
+	for host, lock := range mxSessionLocks {
+		if lock.TryLock() {
+			lock.Unlock()
+			delete(mxSessionLocks, host)
+		}
+	}
+}
+
+// validateEmailsConcurrently verifies each address with a bounded worker
+// pool, preserving the input order in the returned slice.
+func validateEmailsConcurrently(addresses []string) []EmailResult {
+	results := make([]EmailResult, len(addresses))
+
+	sem := make(chan struct{}, maxConcurrency())
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, address string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = validateEmailWithTimeout(address)
+		}(i, address)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func batchJSONHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var addresses []string
+	if err := json.NewDecoder(r.Body).Decode(&addresses); err != nil {
+		http.Error(w, "Invalid JSON body, expected an array of addresses", http.StatusBadRequest)
+		return
+	}
+
+	results := validateEmailsConcurrently(addresses)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+type batchAddresses struct {
+	XMLName   xml.Name `xml:"addresses"`
+	Addresses []string `xml:"address"`
+}
+
+type batchResults struct {
+	XMLName xml.Name      `xml:"results"`
+	Results []EmailResult `xml:"result"`
+}
+
+func batchXMLHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload batchAddresses
+	if err := xml.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid XML body, expected an <addresses> list of <address> elements", http.StatusBadRequest)
+		return
+	}
+
+	results := validateEmailsConcurrently(payload.Addresses)
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(batchResults{Results: results})
+}
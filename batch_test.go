@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMaxConcurrency(t *testing.T) {
+	const key = "MAX_CONCURRENCY"
+	defer os.Unsetenv(key)
+
+	os.Unsetenv(key)
+	if got := maxConcurrency(); got != 10 {
+		t.Errorf("unset env: got %d, want default 10", got)
+	}
+
+	os.Setenv(key, "3")
+	if got := maxConcurrency(); got != 3 {
+		t.Errorf("valid env: got %d, want 3", got)
+	}
+
+	os.Setenv(key, "0")
+	if got := maxConcurrency(); got != 10 {
+		t.Errorf("zero env: got %d, want default 10", got)
+	}
+
+	os.Setenv(key, "not-a-number")
+	if got := maxConcurrency(); got != 10 {
+		t.Errorf("unparsable env: got %d, want default 10", got)
+	}
+}
+
+func TestValidateEmailsConcurrentlyPreservesOrder(t *testing.T) {
+	// All three addresses share a domain with no MX record, so each
+	// resolves deterministically and quickly without touching the network.
+	addresses := []string{
+		"a@nonexistent-domain-zzz.invalid",
+		"b@nonexistent-domain-zzz.invalid",
+		"c@nonexistent-domain-zzz.invalid",
+	}
+
+	results := validateEmailsConcurrently(addresses)
+	if len(results) != len(addresses) {
+		t.Fatalf("got %d results, want %d", len(results), len(addresses))
+	}
+	for i, want := range addresses {
+		if results[i].Address != want {
+			t.Errorf("result[%d].Address = %q, want %q (order not preserved)", i, results[i].Address, want)
+		}
+	}
+}
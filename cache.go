@@ -0,0 +1,161 @@
+package main
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// envDuration reads a TTL from an env var holding either a plain integer
+// (seconds) or a Go duration string (e.g. "15m"), defaulting to def when
+// unset or unparsable.
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return d
+	}
+	return def
+}
+
+type mxCacheEntry struct {
+	records       []*net.MX
+	err           error
+	misconfigured bool
+	expiresAt     time.Time
+}
+
+var (
+	mxCacheMu sync.RWMutex
+	mxCache   = map[string]mxCacheEntry{}
+)
+
+func mxCacheTTL() time.Duration {
+	return envDuration("MX_CACHE_TTL", 15*time.Minute)
+}
+
+// lookupMXCached wraps net.LookupMX with an in-process TTL cache so bulk
+// verification doesn't issue a fresh DNS round trip for every request.
+// Lookup errors (including NXDOMAIN) are cached too, so a bad domain only
+// costs one resolver round trip per TTL window instead of one per request.
+// The misconfigured-MX check (which resolves every MX host's A/AAAA
+// records) rides the same cache entry via mxMisconfigured, so it's paid
+// once per TTL window rather than once per request too.
+func lookupMXCached(domain string) ([]*net.MX, error) {
+	domain = strings.ToLower(domain)
+
+	mxCacheMu.RLock()
+	entry, ok := mxCache[domain]
+	mxCacheMu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.records, entry.err
+	}
+
+	records, err := net.LookupMX(domain)
+	misconfigured := err == nil && isMisconfiguredMX(records)
+
+	mxCacheMu.Lock()
+	mxCache[domain] = mxCacheEntry{
+		records:       records,
+		err:           err,
+		misconfigured: misconfigured,
+		expiresAt:     time.Now().Add(mxCacheTTL()),
+	}
+	mxCacheMu.Unlock()
+
+	return records, err
+}
+
+// mxMisconfigured reports whether domain's cached MX lookup found only
+// loopback/private/unresolvable hosts. Call after lookupMXCached, which
+// populates the shared cache entry this reads from.
+func mxMisconfigured(domain string) bool {
+	domain = strings.ToLower(domain)
+
+	mxCacheMu.RLock()
+	defer mxCacheMu.RUnlock()
+
+	entry, ok := mxCache[domain]
+	return ok && time.Now().Before(entry.expiresAt) && entry.misconfigured
+}
+
+type smtpCacheEntry struct {
+	deliverable bool
+	fullInbox   bool
+	catchAll    bool
+	expiresAt   time.Time
+}
+
+var (
+	smtpCacheMu sync.RWMutex
+	smtpCache   = map[string]smtpCacheEntry{}
+)
+
+func smtpCacheTTL() time.Duration {
+	return envDuration("SMTP_CACHE_TTL", 60*time.Second)
+}
+
+// cacheSweepInterval controls how often sweepExpiredCaches runs.
+func cacheSweepInterval() time.Duration {
+	return envDuration("CACHE_SWEEP_INTERVAL", 5*time.Minute)
+}
+
+// sweepExpiredCaches removes mxCache/smtpCache entries past their TTL.
+// Lookups already treat an expired entry as a miss and recompute it, but
+// without this they're never actually removed from the map, so the batch
+// endpoint's unbounded, unauthenticated address list could grow both caches
+// forever.
+func sweepExpiredCaches() {
+	now := time.Now()
+
+	mxCacheMu.Lock()
+	for domain, entry := range mxCache {
+		if now.After(entry.expiresAt) {
+			delete(mxCache, domain)
+		}
+	}
+	mxCacheMu.Unlock()
+
+	smtpCacheMu.Lock()
+	for addr, entry := range smtpCache {
+		if now.After(entry.expiresAt) {
+			delete(smtpCache, addr)
+		}
+	}
+	smtpCacheMu.Unlock()
+}
+
+// checkSMTPDeliverableCached wraps checkSMTPDeliverable with a short-TTL
+// cache keyed by address, so retries and bursts for the same address
+// within the TTL window don't open a second SMTP session.
+func checkSMTPDeliverableCached(email, domain string) (deliverable, fullInbox, catchAll bool) {
+	key := strings.ToLower(email)
+
+	smtpCacheMu.RLock()
+	entry, ok := smtpCache[key]
+	smtpCacheMu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.deliverable, entry.fullInbox, entry.catchAll
+	}
+
+	deliverable, fullInbox, catchAll = checkSMTPDeliverable(email, domain)
+
+	smtpCacheMu.Lock()
+	smtpCache[key] = smtpCacheEntry{
+		deliverable: deliverable,
+		fullInbox:   fullInbox,
+		catchAll:    catchAll,
+		expiresAt:   time.Now().Add(smtpCacheTTL()),
+	}
+	smtpCacheMu.Unlock()
+
+	return deliverable, fullInbox, catchAll
+}
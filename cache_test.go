@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvDuration(t *testing.T) {
+	const key = "TEST_ENV_DURATION"
+	defer os.Unsetenv(key)
+
+	if got := envDuration(key, 15*time.Minute); got != 15*time.Minute {
+		t.Errorf("unset env: got %v, want default 15m", got)
+	}
+
+	os.Setenv(key, "90")
+	if got := envDuration(key, 15*time.Minute); got != 90*time.Second {
+		t.Errorf("plain seconds: got %v, want 90s", got)
+	}
+
+	os.Setenv(key, "2m")
+	if got := envDuration(key, 15*time.Minute); got != 2*time.Minute {
+		t.Errorf("duration string: got %v, want 2m", got)
+	}
+
+	os.Setenv(key, "not-a-duration")
+	if got := envDuration(key, 15*time.Minute); got != 15*time.Minute {
+		t.Errorf("unparsable: got %v, want default 15m", got)
+	}
+}
+
+func TestCheckSMTPDeliverableCachedHonorsFreshEntry(t *testing.T) {
+	key := strings.ToLower("fresh-entry@example.com")
+	smtpCacheMu.Lock()
+	smtpCache[key] = smtpCacheEntry{
+		deliverable: true,
+		fullInbox:   true,
+		catchAll:    true,
+		expiresAt:   time.Now().Add(time.Minute),
+	}
+	smtpCacheMu.Unlock()
+
+	deliverable, fullInbox, catchAll := checkSMTPDeliverableCached("fresh-entry@example.com", "example.com")
+	if !deliverable || !fullInbox || !catchAll {
+		t.Fatalf("expected fresh cache entry to be returned as-is, got deliverable=%v fullInbox=%v catchAll=%v", deliverable, fullInbox, catchAll)
+	}
+}
+
+func TestCheckSMTPDeliverableCachedBypassesExpiredEntry(t *testing.T) {
+	// A domain with no MX record resolves deterministically to
+	// false/false/false without touching the network, so an expired cache
+	// entry seeded with the opposite values proves the expiry check, not
+	// whatever a live SMTP probe happens to return.
+	const address = "expired-entry@nonexistent-domain-zzz.invalid"
+	const domain = "nonexistent-domain-zzz.invalid"
+
+	key := strings.ToLower(address)
+	smtpCacheMu.Lock()
+	smtpCache[key] = smtpCacheEntry{
+		deliverable: true,
+		fullInbox:   true,
+		catchAll:    true,
+		expiresAt:   time.Now().Add(-time.Minute),
+	}
+	smtpCacheMu.Unlock()
+
+	deliverable, fullInbox, catchAll := checkSMTPDeliverableCached(address, domain)
+	if deliverable || fullInbox || catchAll {
+		t.Fatalf("expected expired cache entry to be bypassed and recomputed as false, got deliverable=%v fullInbox=%v catchAll=%v", deliverable, fullInbox, catchAll)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"embed"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed disposable_domains.txt
+var bundledDisposableDomains embed.FS
+
+// DisposableChecker answers whether an email domain belongs to a known
+// disposable/temporary-email provider. Its domain set is built from a
+// bundled default list, an optional local file (DISPOSABLE_LIST_PATH),
+// and an optional remote list (DISPOSABLE_LIST_URL, e.g. pointing at the
+// community-maintained disposable-email-domains repo) refetched
+// periodically in a background goroutine. Lookups take an RWMutex, and
+// refreshes swap in a whole new set rather than mutating it in place, so
+// readers never see a half-populated list.
+type DisposableChecker struct {
+	mu       sync.RWMutex
+	exact    map[string]struct{}
+	wildcard []string // suffixes, already stripped of their "*." prefix
+
+	baseLines []string // bundled + local file entries, kept across refreshes
+}
+
+var disposableChecker = newDisposableChecker()
+
+func newDisposableChecker() *DisposableChecker {
+	c := &DisposableChecker{}
+
+	lines := readBundledDisposableLines()
+	if path := os.Getenv("DISPOSABLE_LIST_PATH"); path != "" {
+		fileLines, err := readLinesFile(path)
+		if err != nil {
+			log.Printf("disposable: failed to read %s: %v", path, err)
+		} else {
+			lines = append(lines, fileLines...)
+		}
+	}
+	c.baseLines = lines
+	c.swap(lines)
+
+	if url := os.Getenv("DISPOSABLE_LIST_URL"); url != "" {
+		go c.refreshLoop(url, disposableRefreshInterval())
+	}
+
+	return c
+}
+
+func disposableRefreshInterval() time.Duration {
+	return envDuration("DISPOSABLE_LIST_REFRESH_INTERVAL", 6*time.Hour)
+}
+
+func readBundledDisposableLines() []string {
+	data, err := bundledDisposableDomains.ReadFile("disposable_domains.txt")
+	if err != nil {
+		log.Printf("disposable: failed to read bundled list: %v", err)
+		return nil
+	}
+	return parseDomainLines(strings.NewReader(string(data)))
+}
+
+func readLinesFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseDomainLines(f), nil
+}
+
+// parseDomainLines reads one domain per line, skipping blanks and
+// "#"-prefixed comments.
+func parseDomainLines(r interface {
+	Read(p []byte) (n int, err error)
+}) []string {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// swap atomically rebuilds the exact/wildcard sets from lines.
+func (c *DisposableChecker) swap(lines []string) {
+	exact := make(map[string]struct{}, len(lines))
+	var wildcard []string
+
+	for _, line := range lines {
+		domain := strings.ToLower(strings.TrimSpace(line))
+		if domain == "" {
+			continue
+		}
+		if strings.HasPrefix(domain, "*.") {
+			wildcard = append(wildcard, strings.TrimPrefix(domain, "*."))
+			continue
+		}
+		exact[domain] = struct{}{}
+	}
+
+	c.mu.Lock()
+	c.exact = exact
+	c.wildcard = wildcard
+	c.mu.Unlock()
+}
+
+// IsDisposable reports whether domain, or a parent domain matched by a
+// wildcard entry, is a known disposable-email provider.
+func (c *DisposableChecker) IsDisposable(domain string) bool {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.exact[domain]; ok {
+		return true
+	}
+	for _, suffix := range c.wildcard {
+		if domain == suffix || strings.HasSuffix(domain, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *DisposableChecker) refreshLoop(url string, interval time.Duration) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.refreshFromURL(client, url); err != nil {
+			log.Printf("disposable: failed to refresh list from %s: %v", url, err)
+		}
+		<-ticker.C
+	}
+}
+
+// refreshFromURL re-fetches the remote list and swaps it in alongside the
+// bundled/local-file base lines, replacing whatever the previous fetch
+// contributed rather than accumulating stale entries forever.
+func (c *DisposableChecker) refreshFromURL(client *http.Client, url string) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	remoteLines := parseDomainLines(resp.Body)
+
+	lines := make([]string, 0, len(c.baseLines)+len(remoteLines))
+	lines = append(lines, c.baseLines...)
+	lines = append(lines, remoteLines...)
+	c.swap(lines)
+
+	return nil
+}
+
+// isDisposableEmail reports whether domain belongs to a known disposable
+// email provider, per the process-wide DisposableChecker.
+func isDisposableEmail(domain string) bool {
+	return disposableChecker.IsDisposable(domain)
+}
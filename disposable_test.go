@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestDisposableCheckerExactAndWildcardMatching(t *testing.T) {
+	c := &DisposableChecker{}
+	c.swap([]string{
+		"mailinator.com",
+		"*.trashmail.com",
+	})
+
+	cases := []struct {
+		domain string
+		want   bool
+	}{
+		{"mailinator.com", true},
+		{"MAILINATOR.COM", true},      // case-insensitive
+		{"sub.mailinator.com", false}, // exact entry shouldn't match subdomains
+		{"trashmail.com", true},       // wildcard entry also matches its own apex
+		{"sub.trashmail.com", true},
+		{"deep.sub.trashmail.com", true},
+		{"example.com", false},
+		{"nottrashmail.com", false}, // must not match as a bare suffix without a dot
+	}
+
+	for _, c2 := range cases {
+		if got := c.IsDisposable(c2.domain); got != c2.want {
+			t.Errorf("IsDisposable(%q) = %v, want %v", c2.domain, got, c2.want)
+		}
+	}
+}
+
+func TestDisposableCheckerSwapReplacesPreviousSet(t *testing.T) {
+	c := &DisposableChecker{}
+	c.swap([]string{"old-disposable.com"})
+	if !c.IsDisposable("old-disposable.com") {
+		t.Fatal("expected old-disposable.com to be disposable before swap")
+	}
+
+	c.swap([]string{"new-disposable.com"})
+	if c.IsDisposable("old-disposable.com") {
+		t.Fatal("expected old-disposable.com to no longer be disposable after swap")
+	}
+	if !c.IsDisposable("new-disposable.com") {
+		t.Fatal("expected new-disposable.com to be disposable after swap")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net"
+	"strings"
+)
+
+var roleAccountLocalParts = map[string]struct{}{
+	"admin":         {},
+	"administrator": {},
+	"abuse":         {},
+	"billing":       {},
+	"contact":       {},
+	"hostmaster":    {},
+	"info":          {},
+	"marketing":     {},
+	"no-reply":      {},
+	"noreply":       {},
+	"postmaster":    {},
+	"privacy":       {},
+	"root":          {},
+	"sales":         {},
+	"security":      {},
+	"support":       {},
+	"sysadmin":      {},
+	"tech":          {},
+	"webmaster":     {},
+}
+
+// isRoleAccount reports whether username names a role/shared mailbox
+// (admin@, support@, postmaster@, ...) rather than an individual.
+func isRoleAccount(username string) bool {
+	_, ok := roleAccountLocalParts[strings.ToLower(username)]
+	return ok
+}
+
+var freeProviderDomains = map[string]struct{}{
+	"gmail.com":      {},
+	"yahoo.com":      {},
+	"outlook.com":    {},
+	"hotmail.com":    {},
+	"live.com":       {},
+	"msn.com":        {},
+	"aol.com":        {},
+	"icloud.com":     {},
+	"protonmail.com": {},
+	"mail.com":       {},
+	"gmx.com":        {},
+	"yandex.com":     {},
+	"zoho.com":       {},
+}
+
+// isFreeProvider reports whether domain belongs to a well-known free
+// webmail provider.
+func isFreeProvider(domain string) bool {
+	_, ok := freeProviderDomains[strings.ToLower(domain)]
+	return ok
+}
+
+// isMisconfiguredMX reports whether every MX host for a domain resolves to
+// a loopback/private address or doesn't resolve at all — a common sign an
+// admin set up an MX record incorrectly and mail to it will bounce.
+func isMisconfiguredMX(mxRecords []*net.MX) bool {
+	if len(mxRecords) == 0 {
+		return false
+	}
+	for _, mx := range mxRecords {
+		if !isLoopbackOrUnresolvable(strings.TrimSuffix(mx.Host, ".")) {
+			return false
+		}
+	}
+	return true
+}
+
+func isLoopbackOrUnresolvable(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified()
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil || len(ips) == 0 {
+		return true
+	}
+	for _, ip := range ips {
+		if !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() {
+			return false
+		}
+	}
+	return true
+}
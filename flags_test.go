@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsLoopbackOrUnresolvable(t *testing.T) {
+	cases := []struct {
+		host string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.0.0.5", true},    // private
+		{"192.168.1.1", true}, // private
+		{"0.0.0.0", true},     // unspecified
+		{"8.8.8.8", false},    // literal public IP, no DNS lookup needed
+	}
+
+	for _, c := range cases {
+		if got := isLoopbackOrUnresolvable(c.host); got != c.want {
+			t.Errorf("isLoopbackOrUnresolvable(%q) = %v, want %v", c.host, got, c.want)
+		}
+	}
+}
+
+func TestIsMisconfiguredMX(t *testing.T) {
+	cases := []struct {
+		name string
+		mx   []*net.MX
+		want bool
+	}{
+		{"no records", nil, false},
+		{"all loopback", []*net.MX{{Host: "127.0.0.1"}, {Host: "::1"}}, true},
+		{"mixed loopback and public", []*net.MX{{Host: "127.0.0.1"}, {Host: "8.8.8.8"}}, false},
+		{"all public", []*net.MX{{Host: "8.8.8.8"}, {Host: "1.1.1.1"}}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isMisconfiguredMX(c.mx); got != c.want {
+				t.Errorf("isMisconfiguredMX(%v) = %v, want %v", c.mx, got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsRoleAccount(t *testing.T) {
+	cases := []struct {
+		username string
+		want     bool
+	}{
+		{"postmaster", true},
+		{"Admin", true}, // case-insensitive
+		{"noreply", true},
+		{"no-reply", true},
+		{"jane.doe", false},
+		{"sales-jane", false}, // not an exact match
+	}
+
+	for _, c := range cases {
+		if got := isRoleAccount(c.username); got != c.want {
+			t.Errorf("isRoleAccount(%q) = %v, want %v", c.username, got, c.want)
+		}
+	}
+}
+
+func TestIsFreeProvider(t *testing.T) {
+	cases := []struct {
+		domain string
+		want   bool
+	}{
+		{"gmail.com", true},
+		{"Yahoo.com", true}, // case-insensitive
+		{"outlook.com", true},
+		{"example.com", false},
+		{"mail.gmail.com", false}, // must be the domain itself, not a subdomain
+	}
+
+	for _, c := range cases {
+		if got := isFreeProvider(c.domain); got != c.want {
+			t.Errorf("isFreeProvider(%q) = %v, want %v", c.domain, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+var gravatarHTTPClient = &http.Client{Timeout: 3 * time.Second}
+
+// gravatarBaseURL is the Gravatar avatar endpoint, overridable in tests so
+// checkGravatar can be exercised against an httptest.Server instead of the
+// real network.
+var gravatarBaseURL = "https://www.gravatar.com/avatar/"
+
+// gravatarEnabled reports whether the Gravatar existence check is turned on
+// via ENABLE_GRAVATAR=1. It's opt-in because it adds an external HTTP
+// dependency to every validation.
+func gravatarEnabled() bool {
+	return os.Getenv("ENABLE_GRAVATAR") == "1"
+}
+
+// checkGravatar reports whether a Gravatar image is registered for email.
+// Per Gravatar's API, a GET for the email's lowercased, trimmed MD5 hash
+// returns 200 if an avatar exists and 404 (via d=404) if not.
+func checkGravatar(email string) bool {
+	hash := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(email))))
+	url := gravatarBaseURL + hex.EncodeToString(hash[:]) + "?d=404"
+
+	resp, err := gravatarHTTPClient.Get(url)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
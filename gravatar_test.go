@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGravatarEnabled(t *testing.T) {
+	const key = "ENABLE_GRAVATAR"
+	defer os.Unsetenv(key)
+
+	os.Unsetenv(key)
+	if gravatarEnabled() {
+		t.Error("unset env: expected gravatarEnabled() to be false")
+	}
+
+	os.Setenv(key, "1")
+	if !gravatarEnabled() {
+		t.Error(`env=1: expected gravatarEnabled() to be true`)
+	}
+
+	os.Setenv(key, "true")
+	if gravatarEnabled() {
+		t.Error(`env=true: expected gravatarEnabled() to be false (only "1" enables it)`)
+	}
+}
+
+func TestCheckGravatar(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("d") != "404" {
+			t.Errorf("expected d=404 query param, got %q", r.URL.RawQuery)
+		}
+		if strings.HasPrefix(r.URL.Path, "/has-avatar/") {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	origBaseURL := gravatarBaseURL
+	defer func() { gravatarBaseURL = origBaseURL }()
+
+	gravatarBaseURL = srv.URL + "/has-avatar/"
+	if !checkGravatar("someone@example.com") {
+		t.Error("expected checkGravatar to return true for a 200 response")
+	}
+
+	gravatarBaseURL = srv.URL + "/no-avatar/"
+	if checkGravatar("someone@example.com") {
+		t.Error("expected checkGravatar to return false for a 404 response")
+	}
+}
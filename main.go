@@ -4,30 +4,37 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"log"
+	"math/rand"
 	"net"
 	"net/http"
 	"net/smtp"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
 type EmailResult struct {
-	XMLName     xml.Name `json:"-" xml:"result"`
-	Address     string   `json:"address" xml:"address"`
-	Username    string   `json:"username" xml:"username"`
-	Domain      string   `json:"domain" xml:"domain"`
-	HostExists  bool     `json:"hostExists" xml:"hostExists"`
-	Deliverable bool     `json:"deliverable" xml:"deliverable"`
-	FullInbox   bool     `json:"fullInbox" xml:"fullInbox"`
-	CatchAll    bool     `json:"catchAll" xml:"catchAll"`
-	Disposable  bool     `json:"disposable" xml:"disposable"`
-	Gravatar    bool     `json:"gravatar" xml:"gravatar"`
+	XMLName         xml.Name `json:"-" xml:"result"`
+	Address         string   `json:"address" xml:"address"`
+	Username        string   `json:"username" xml:"username"`
+	Domain          string   `json:"domain" xml:"domain"`
+	HostExists      bool     `json:"hostExists" xml:"hostExists"`
+	Deliverable     bool     `json:"deliverable" xml:"deliverable"`
+	FullInbox       bool     `json:"fullInbox" xml:"fullInbox"`
+	CatchAll        bool     `json:"catchAll" xml:"catchAll"`
+	Disposable      bool     `json:"disposable" xml:"disposable"`
+	Gravatar        bool     `json:"gravatar" xml:"gravatar"`
+	RoleAccount     bool     `json:"roleAccount" xml:"roleAccount"`
+	FreeProvider    bool     `json:"freeProvider" xml:"freeProvider"`
+	MisconfiguredMX bool     `json:"misconfiguredMX" xml:"misconfiguredMX"`
 }
 
 func main() {
 	// Routes matching Trumail API
+	http.HandleFunc("/v1/json/batch", batchJSONHandler)
+	http.HandleFunc("/v1/xml/batch", batchXMLHandler)
 	http.HandleFunc("/v1/json/", validateEmailJSONHandler)
 	http.HandleFunc("/v1/xml/", validateEmailXMLHandler)
 	http.HandleFunc("/v1/health", healthCheckHandler)
@@ -42,6 +49,8 @@ func main() {
 	log.Printf("Available endpoints:")
 	log.Printf("  GET /v1/json/{email}")
 	log.Printf("  GET /v1/xml/{email}")
+	log.Printf("  POST /v1/json/batch")
+	log.Printf("  POST /v1/xml/batch")
 	log.Printf("  GET /v1/health")
 
 	log.Fatal(http.ListenAndServe(":"+port, nil))
@@ -84,17 +93,7 @@ func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 func validateEmail(email string) EmailResult {
 	parts := strings.Split(email, "@")
 	if len(parts) != 2 {
-		return EmailResult{
-			Address:     email,
-			Username:    "",
-			Domain:      "",
-			HostExists:  false,
-			Deliverable: false,
-			FullInbox:   false,
-			CatchAll:    false,
-			Disposable:  false,
-			Gravatar:    false,
-		}
+		return EmailResult{Address: email}
 	}
 
 	username := parts[0]
@@ -103,70 +102,109 @@ func validateEmail(email string) EmailResult {
 	// Basic email format validation
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	if !emailRegex.MatchString(email) {
-		return EmailResult{
-			Address:     email,
-			Username:    username,
-			Domain:      domain,
-			HostExists:  false,
-			Deliverable: false,
-			FullInbox:   false,
-			CatchAll:    false,
-			Disposable:  false,
-			Gravatar:    false,
-		}
+		return EmailResult{Address: email, Username: username, Domain: domain}
 	}
 
 	// Check if domain has MX record
-	hostExists := checkMXRecord(domain)
+	hostExists, misconfiguredMX := checkMXRecord(domain)
+
+	// The Gravatar lookup is an independent HTTP call, so run it alongside
+	// the SMTP probe instead of after it.
+	var gravatar bool
+	var wg sync.WaitGroup
+	if gravatarEnabled() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gravatar = checkGravatar(email)
+		}()
+	}
 
 	// Attempt SMTP verification
-	deliverable := false
+	deliverable, fullInbox, catchAll := false, false, false
 	if hostExists {
-		deliverable = checkSMTPDeliverable(email, domain)
+		deliverable, fullInbox, catchAll = checkSMTPDeliverableCached(email, domain)
 	}
 
+	wg.Wait()
+
 	// Check for disposable email domains
 	disposable := isDisposableEmail(domain)
 
 	return EmailResult{
-		Address:     email,
-		Username:    username,
-		Domain:      domain,
-		HostExists:  hostExists,
-		Deliverable: deliverable,
-		FullInbox:   false,
-		CatchAll:    false,
-		Disposable:  disposable,
-		Gravatar:    false,
+		Address:         email,
+		Username:        username,
+		Domain:          domain,
+		HostExists:      hostExists,
+		Deliverable:     deliverable,
+		FullInbox:       fullInbox,
+		CatchAll:        catchAll,
+		Disposable:      disposable,
+		Gravatar:        gravatar,
+		RoleAccount:     isRoleAccount(username),
+		FreeProvider:    isFreeProvider(domain),
+		MisconfiguredMX: misconfiguredMX,
 	}
 }
 
-func checkMXRecord(domain string) bool {
-	_, err := net.LookupMX(domain)
-	return err == nil
+// checkMXRecord reports whether domain has an MX record and whether every
+// MX host it does have is misconfigured (loopback/private/unresolvable),
+// which is a common sign an admin set up DNS incorrectly and mail will
+// bounce even though the record exists.
+func checkMXRecord(domain string) (hostExists, misconfiguredMX bool) {
+	mxRecords, err := lookupMXCached(domain)
+	if err != nil || len(mxRecords) == 0 {
+		return false, false
+	}
+	return true, mxMisconfigured(domain)
 }
 
-func checkSMTPDeliverable(email, domain string) bool {
+// checkSMTPDeliverable reports whether email is deliverable, whether its
+// mailbox is full, and whether its domain accepts RCPT for any local part
+// (catch-all), which means the deliverable result can't be trusted.
+func checkSMTPDeliverable(email, domain string) (deliverable, fullInbox, catchAll bool) {
 	// Get MX records
-	mxRecords, err := net.LookupMX(domain)
+	mxRecords, err := lookupMXCached(domain)
 	if err != nil || len(mxRecords) == 0 {
-		return false
+		return false, false, false
 	}
 
 	// Try to connect to the first MX server
 	mxHost := strings.TrimSuffix(mxRecords[0].Host, ".")
 
+	// Large providers grey-list or tarpit SMTP probes, so prefer a
+	// provider-specific API verifier over RCPT when one is registered for
+	// this MX host.
+	if verifier, ok := lookupAPIVerifier(mxHost); ok {
+		username := strings.SplitN(email, "@", 2)[0]
+		if result, err := verifier.Check(username, domain); err == nil {
+			return result.Deliverable, false, false
+		}
+	}
+
+	// Only one SMTP session per remote MX at a time, so a bulk batch
+	// doesn't open dozens of parallel connections and get blocklisted.
+	lock := mxSessionLock(mxHost)
+	lock.Lock()
+	defer lock.Unlock()
+
 	// Set up connection with timeout
 	conn, err := net.DialTimeout("tcp", mxHost+":25", 10*time.Second)
 	if err != nil {
-		return false
+		return false, false, false
 	}
 	defer conn.Close()
 
+	// Bound the whole HELO/MAIL/RCPT dialogue so a server that tarpits past
+	// the connect step can't hold mxSessionLock(mxHost) forever.
+	if err := conn.SetDeadline(time.Now().Add(smtpSessionTimeout())); err != nil {
+		return false, false, false
+	}
+
 	// Create SMTP client
 	client, err := smtp.NewClient(conn, mxHost)
 	if err != nil {
-		return false
+		return false, false, false
 	}
 	defer client.Quit()
 
@@ -178,31 +216,79 @@ func checkSMTPDeliverable(email, domain string) bool {
 
 	err = client.Hello("trumail-validator.com")
 	if err != nil {
-		return false
+		return false, false, false
 	}
 
 	// MAIL FROM
 	err = client.Mail(sourceAddr)
 	if err != nil {
-		return false
+		return false, false, false
 	}
 
-	// RCPT TO - this is where we test if the email exists
-	err = client.Rcpt(email)
-	return err == nil
+	// RCPT TO - this is where we test if the email exists. We use the
+	// lower-level Text.Cmd/ReadResponse flow instead of client.Rcpt so we
+	// can classify the numeric reply code, not just success/failure.
+	code, msg, err := smtpCmd(client, "RCPT TO:<%s>", email)
+	if err != nil {
+		return false, false, false
+	}
+	deliverable = code == 250
+	fullInbox = isFullInboxResponse(code, msg)
+
+	// Catch-all detection: a second RCPT for a random, almost-certainly
+	// nonexistent local part on the same session. If the server accepts it
+	// too, it accepts everything, so the first RCPT result is meaningless.
+	if deliverable {
+		probe := randomLocalPart(20) + "@" + domain
+		if probeCode, _, probeErr := smtpCmd(client, "RCPT TO:<%s>", probe); probeErr == nil && probeCode == 250 {
+			// The server accepts RCPT for an almost-certainly nonexistent
+			// address too, so it accepts everything on this domain. Leave
+			// deliverable as the literal RCPT result and let callers use
+			// CatchAll to discount it, rather than overwriting what the
+			// server actually said.
+			catchAll = true
+		}
+	}
+
+	return deliverable, fullInbox, catchAll
 }
 
-func isDisposableEmail(domain string) bool {
-	disposableDomains := []string{
-		"10minutemail.com", "guerrillamail.com", "mailinator.com",
-		"tempmail.org", "throwaway.email", "temp-mail.org",
+// smtpCmd issues an SMTP command over client's underlying text connection
+// and returns the numeric reply code and message, without client.cmd's
+// unexported expectCode enforcement (which would surface a mismatched code
+// as an error rather than letting the caller classify it).
+func smtpCmd(client *smtp.Client, format string, args ...interface{}) (code int, message string, err error) {
+	id, err := client.Text.Cmd(format, args...)
+	if err != nil {
+		return 0, "", err
 	}
+	client.Text.StartResponse(id)
+	defer client.Text.EndResponse(id)
+	return client.Text.ReadResponse(0)
+}
 
-	domain = strings.ToLower(domain)
-	for _, disposable := range disposableDomains {
-		if domain == disposable {
-			return true
-		}
+// isFullInboxResponse recognizes the reply codes mail servers use to
+// signal an over-quota mailbox: 452 4.2.2, or a 552 whose text mentions
+// quota/fullness.
+func isFullInboxResponse(code int, message string) bool {
+	if code == 452 {
+		return true
+	}
+	if code != 552 {
+		return false
+	}
+	lower := strings.ToLower(message)
+	return strings.Contains(lower, "quota") || strings.Contains(lower, "full") || strings.Contains(lower, "over quota")
+}
+
+const randomLocalPartAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// randomLocalPart generates a random lowercase-alphanumeric local part used
+// to probe for catch-all domains.
+func randomLocalPart(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = randomLocalPartAlphabet[rand.Intn(len(randomLocalPartAlphabet))]
 	}
-	return false
-}
\ No newline at end of file
+	return string(b)
+}
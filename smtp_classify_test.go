@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestIsFullInboxResponse(t *testing.T) {
+	cases := []struct {
+		name    string
+		code    int
+		message string
+		want    bool
+	}{
+		{"452 mailbox full", 452, "4.2.2 Mailbox full", true},
+		{"452 with no text", 452, "", true},
+		{"552 quota", 552, "5.2.2 Quota exceeded", true},
+		{"552 over quota phrase", 552, "mailbox is over quota", true},
+		{"552 full phrase", 552, "recipient mailbox is full", true},
+		{"552 unrelated reason", 552, "message too large", false},
+		{"250 ok", 250, "2.1.5 OK", false},
+		{"550 no such user", 550, "no such user", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isFullInboxResponse(c.code, c.message); got != c.want {
+				t.Errorf("isFullInboxResponse(%d, %q) = %v, want %v", c.code, c.message, got, c.want)
+			}
+		})
+	}
+}
+
+func TestRandomLocalPartLengthAndAlphabet(t *testing.T) {
+	local := randomLocalPart(20)
+	if len(local) != 20 {
+		t.Fatalf("expected length 20, got %d (%q)", len(local), local)
+	}
+	for _, r := range local {
+		if !containsRune(randomLocalPartAlphabet, r) {
+			t.Fatalf("character %q not in allowed alphabet %q", r, randomLocalPartAlphabet)
+		}
+	}
+}
+
+func containsRune(s string, r rune) bool {
+	for _, c := range s {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}